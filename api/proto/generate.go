@@ -0,0 +1,8 @@
+// Package proto holds the generated gRPC stubs for inference.proto.
+//
+// Run `go generate ./...` (with protoc and the protoc-gen-go /
+// protoc-gen-go-grpc plugins on PATH) to regenerate inference.pb.go and
+// inference_grpc.pb.go after editing the .proto file.
+package proto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative inference.proto
@@ -0,0 +1,75 @@
+// Package authz decides whether a client identity (the Subject CN of
+// its mTLS client certificate; SANs are not consulted) may call a given
+// model.
+package authz
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Authorizer decides whether identity may call modelName.
+type Authorizer interface {
+	Authorize(identity, modelName string) bool
+}
+
+// AllowAll authorizes every request; it is the default when no
+// authorization config is supplied, e.g. during local development
+// without mTLS.
+type AllowAll struct{}
+
+// Authorize implements Authorizer.
+func (AllowAll) Authorize(identity, modelName string) bool { return true }
+
+// staticConfig is the on-disk shape of a static authorization file:
+// client CN mapped to the models it may call.
+type staticConfig struct {
+	Identities map[string][]string `yaml:"identities"`
+}
+
+// StaticAuthorizer is a static cn -> allowed_models Authorizer loaded
+// once from YAML.
+type StaticAuthorizer struct {
+	allowed map[string]map[string]bool
+}
+
+// LoadStaticAuthorizer reads a YAML file of the form:
+//
+//	identities:
+//	  client-a.internal:
+//	    - iris_classifier
+//	  client-b.internal:
+//	    - fraud_detector
+//	    - iris_classifier
+func LoadStaticAuthorizer(path string) (*StaticAuthorizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg staticConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[string]map[string]bool, len(cfg.Identities))
+	for identity, models := range cfg.Identities {
+		set := make(map[string]bool, len(models))
+		for _, model := range models {
+			set[model] = true
+		}
+		allowed[identity] = set
+	}
+
+	return &StaticAuthorizer{allowed: allowed}, nil
+}
+
+// Authorize implements Authorizer.
+func (a *StaticAuthorizer) Authorize(identity, modelName string) bool {
+	models, ok := a.allowed[identity]
+	if !ok {
+		return false
+	}
+	return models[modelName]
+}
@@ -0,0 +1,42 @@
+package authz
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Author: Gabriel Demetrios Lafis
+
+func TestAllowAllAuthorizesEverything(t *testing.T) {
+	var a AllowAll
+	assert.True(t, a.Authorize("anyone", "any_model"))
+}
+
+func TestLoadStaticAuthorizer(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "authz.yaml")
+	data := `identities:
+  client-a.internal:
+    - iris_classifier
+  client-b.internal:
+    - fraud_detector
+    - iris_classifier
+`
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	authorizer, err := LoadStaticAuthorizer(path)
+	assert.NoError(t, err)
+
+	assert.True(t, authorizer.Authorize("client-a.internal", "iris_classifier"))
+	assert.False(t, authorizer.Authorize("client-a.internal", "fraud_detector"))
+	assert.True(t, authorizer.Authorize("client-b.internal", "fraud_detector"))
+	assert.False(t, authorizer.Authorize("unknown-client", "iris_classifier"))
+}
+
+func TestLoadStaticAuthorizerMissingFile(t *testing.T) {
+	_, err := LoadStaticAuthorizer("/nonexistent/authz.yaml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,287 @@
+// Package batcher accumulates single-item prediction calls into batches
+// dispatched to a model's PredictBatch, trading a small added latency
+// budget for the throughput of a vectorized model call.
+package batcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ErrBatcherClosed is returned by Predict once Close has been called,
+// e.g. because registry.Registry swapped in a new Batcher for the same
+// model on reload.
+var ErrBatcherClosed = errors.New("batcher: closed")
+
+// Batchable is implemented by any model that can score a batch of
+// feature vectors in a single call.
+type Batchable interface {
+	PredictBatch(features [][]float64) ([]interface{}, []float64, error)
+}
+
+// Options configures batching behavior for a single model.
+type Options struct {
+	MaxBatchSize     int
+	MaxLatencyBudget time.Duration
+}
+
+// DefaultOptions reads BATCH_MAX_SIZE and BATCH_MAX_WAIT_MS, falling
+// back to a batch of 32 items or a 5ms wait, whichever comes first.
+func DefaultOptions() Options {
+	return Options{
+		MaxBatchSize:     envInt("BATCH_MAX_SIZE", 32),
+		MaxLatencyBudget: time.Duration(envInt("BATCH_MAX_WAIT_MS", 5)) * time.Millisecond,
+	}
+}
+
+func envInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+var (
+	batchSizeHistogram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ml_api_batch_size",
+			Help:    "Realized batch size dispatched to a model's PredictBatch",
+			Buckets: prometheus.LinearBuckets(1, 4, 8),
+		},
+		[]string{"model_name"},
+	)
+
+	queueWaitSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ml_api_batch_queue_wait_seconds",
+			Help:    "Time an individual request waited in the batch queue before dispatch",
+			Buckets: []float64{.0001, .0005, .001, .0025, .005, .01, .025, .05},
+		},
+		[]string{"model_name"},
+	)
+)
+
+type queuedRequest struct {
+	features []float64
+	queuedAt time.Time
+	result   chan predictionResult
+}
+
+type predictionResult struct {
+	prediction  interface{}
+	probability float64
+	err         error
+}
+
+// Batcher is a single model's micro-batching worker. Requests submitted
+// via Predict are queued and drained by one dedicated goroutine per
+// Batcher, so a single model is never scored concurrently by two
+// batches.
+type Batcher struct {
+	model Batchable
+	opts  Options
+	queue chan queuedRequest
+
+	// done is closed by Close to stop run once it drains whatever is
+	// already queued. queue itself is never closed: run's collect loop
+	// receives from it without checking for closure, so closing it
+	// would hand run a stream of zero-value queuedRequests (nil
+	// features, nil result) to pad a batch with.
+	done chan struct{}
+
+	// closeMu guards closed and serializes it against every enqueue
+	// attempt, so a request can never be admitted to queue after Close
+	// has already stopped run from reading it (which would otherwise
+	// leave that caller blocked on req.result forever). It's scoped to
+	// just the enqueue step, not Predict's whole call, so Close only
+	// has to wait out requests currently being admitted, not however
+	// long their inference takes. If queue is already full when Close
+	// runs, an in-flight enqueue can still block Close for as long as
+	// its own ctx allows, since it holds this lock across the blocking
+	// send. The HTTP and gRPC surfaces in this repo pass the request's
+	// own ctx, which ends when the client cancels or disconnects (or at
+	// a deadline the gRPC client set), not after a fixed time.
+	closeMu sync.RWMutex
+	closed  bool
+
+	batchSize prometheus.Observer
+	queueWait prometheus.Observer
+}
+
+// New starts a Batcher for model, labeled by modelName (typically
+// "name@version") in the exposed Prometheus metrics.
+func New(modelName string, model Batchable, opts Options) *Batcher {
+	b := &Batcher{
+		model:     model,
+		opts:      opts,
+		queue:     make(chan queuedRequest, opts.MaxBatchSize*4),
+		done:      make(chan struct{}),
+		batchSize: batchSizeHistogram.WithLabelValues(modelName),
+		queueWait: queueWaitSeconds.WithLabelValues(modelName),
+	}
+	go b.run()
+	return b
+}
+
+// Predict submits a single feature vector and blocks until its result is
+// ready, ctx is done, or the Batcher has been Closed.
+func (b *Batcher) Predict(ctx context.Context, features []float64) (interface{}, float64, error) {
+	req := queuedRequest{
+		features: features,
+		queuedAt: time.Now(),
+		result:   make(chan predictionResult, 1),
+	}
+
+	if err := b.enqueue(ctx, req); err != nil {
+		return nil, 0, err
+	}
+
+	select {
+	case res := <-req.result:
+		return res.prediction, res.probability, res.err
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+}
+
+// enqueue admits req to queue under closeMu's read lock, so it can
+// never succeed after Close has already taken the write lock (and
+// therefore stopped run from ever reading req back out).
+func (b *Batcher) enqueue(ctx context.Context, req queuedRequest) error {
+	b.closeMu.RLock()
+	defer b.closeMu.RUnlock()
+
+	if b.closed {
+		return ErrBatcherClosed
+	}
+
+	select {
+	case b.queue <- req:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the Batcher's worker goroutine, failing any request
+// still sitting in its queue with ErrBatcherClosed rather than leaving
+// it to wait on its caller's ctx. Call it once the Batcher is no
+// longer reachable (e.g. after registry.Registry swaps in a new one
+// for the same model on reload) so its goroutine doesn't run forever.
+// Safe to call more than once.
+func (b *Batcher) Close() {
+	b.closeMu.Lock()
+	defer b.closeMu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.done)
+}
+
+// run drains the queue, draining up to MaxBatchSize items or waiting at
+// most MaxLatencyBudget for more to arrive before dispatching.
+func (b *Batcher) run() {
+	for {
+		// Checked before the main select below, not as one of its
+		// cases: once done is closed, a select across both done and a
+		// non-empty queue picks between them at random, which could
+		// start a fresh batch instead of draining. A select with a
+		// default only falls through to it when the done case isn't
+		// ready, so this deterministically wins once Close has run.
+		select {
+		case <-b.done:
+			b.drain()
+			return
+		default:
+		}
+
+		var first queuedRequest
+		select {
+		case first = <-b.queue:
+		case <-b.done:
+			b.drain()
+			return
+		}
+
+		// Close may have run the instant after the receive above: both
+		// cases were momentarily ready together, and an unguarded select
+		// would have picked between dispatching first and draining it
+		// at random. Re-check done, non-blocking, so a request that
+		// lands on the closed side of that race is always failed with
+		// ErrBatcherClosed rather than sometimes slipping through to a
+		// model whose Batcher the registry has already replaced.
+		select {
+		case <-b.done:
+			first.result <- predictionResult{err: ErrBatcherClosed}
+			b.drain()
+			return
+		default:
+		}
+
+		batch := []queuedRequest{first}
+
+		timer := time.NewTimer(b.opts.MaxLatencyBudget)
+	collect:
+		for len(batch) < b.opts.MaxBatchSize {
+			select {
+			case req := <-b.queue:
+				batch = append(batch, req)
+			case <-timer.C:
+				break collect
+			case <-b.done:
+				break collect
+			}
+		}
+		timer.Stop()
+
+		b.dispatch(batch)
+	}
+}
+
+// drain fails every request already sitting in queue when Close is
+// called, so a caller that enqueued just before Close doesn't block
+// waiting for a result run will never produce.
+func (b *Batcher) drain() {
+	for {
+		select {
+		case req := <-b.queue:
+			req.result <- predictionResult{err: ErrBatcherClosed}
+		default:
+			return
+		}
+	}
+}
+
+func (b *Batcher) dispatch(batch []queuedRequest) {
+	b.batchSize.Observe(float64(len(batch)))
+
+	features := make([][]float64, len(batch))
+	for i, req := range batch {
+		features[i] = req.features
+		b.queueWait.Observe(time.Since(req.queuedAt).Seconds())
+	}
+
+	predictions, probabilities, err := b.model.PredictBatch(features)
+
+	for i, req := range batch {
+		if err != nil {
+			req.result <- predictionResult{err: err}
+			continue
+		}
+		req.result <- predictionResult{prediction: predictions[i], probability: probabilities[i]}
+	}
+}
@@ -0,0 +1,106 @@
+package batcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Author: Gabriel Demetrios Lafis
+
+// fakeModel doubles every feature's first value, so a test can assert
+// on a predictable, per-request prediction without depending on any
+// registry.Model implementation.
+type fakeModel struct{}
+
+func (fakeModel) PredictBatch(features [][]float64) ([]interface{}, []float64, error) {
+	predictions := make([]interface{}, len(features))
+	probabilities := make([]float64, len(features))
+	for i, f := range features {
+		predictions[i] = f[0] * 2
+		probabilities[i] = 1
+	}
+	return predictions, probabilities, nil
+}
+
+func TestBatcherPredictReturnsModelOutput(t *testing.T) {
+	b := New("test@1.0.0", fakeModel{}, Options{MaxBatchSize: 4, MaxLatencyBudget: 5 * time.Millisecond})
+
+	prediction, _, err := b.Predict(context.Background(), []float64{21})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(42), prediction)
+}
+
+func TestBatcherDispatchesFullBatchWithoutWaitingOutLatencyBudget(t *testing.T) {
+	b := New("test@1.0.0", fakeModel{}, Options{MaxBatchSize: 2, MaxLatencyBudget: time.Minute})
+
+	results := make(chan interface{}, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			prediction, _, err := b.Predict(context.Background(), []float64{float64(i)})
+			assert.NoError(t, err)
+			results <- prediction
+		}(i)
+	}
+
+	assert.Eventually(t, func() bool { return len(results) == 2 }, time.Second, time.Millisecond)
+}
+
+func TestBatcherCloseFailsPredictCallsThatHaventEnqueuedYet(t *testing.T) {
+	b := New("test@1.0.0", fakeModel{}, DefaultOptions())
+	b.Close()
+
+	_, _, err := b.Predict(context.Background(), []float64{1})
+	assert.ErrorIs(t, err, ErrBatcherClosed)
+}
+
+func TestBatcherCloseIsSafeToCallTwice(t *testing.T) {
+	b := New("test@1.0.0", fakeModel{}, DefaultOptions())
+	b.Close()
+	b.Close()
+}
+
+// blockingModel blocks in PredictBatch until unblock is closed, so a
+// test can fill a Batcher's queue behind a batch that's stuck
+// dispatching, then close it and confirm the still-queued requests are
+// failed instead of left to hang.
+type blockingModel struct {
+	unblock chan struct{}
+}
+
+func (m blockingModel) PredictBatch(features [][]float64) ([]interface{}, []float64, error) {
+	<-m.unblock
+	return nil, nil, errors.New("blockingModel: released by unblock")
+}
+
+func TestBatcherCloseDrainsQueuedRequestsInsteadOfHanging(t *testing.T) {
+	unblock := make(chan struct{})
+
+	b := New("test@1.0.0", blockingModel{unblock: unblock}, Options{MaxBatchSize: 1, MaxLatencyBudget: time.Minute})
+
+	// Occupy the single-item batch so run() is blocked inside dispatch,
+	// then queue a second request that will never be picked up by a
+	// batch of its own.
+	go b.Predict(context.Background(), []float64{1})
+	assert.Eventually(t, func() bool { return len(b.queue) == 0 }, time.Second, time.Millisecond)
+
+	queuedErrCh := make(chan error, 1)
+	go func() {
+		_, _, err := b.Predict(context.Background(), []float64{2})
+		queuedErrCh <- err
+	}()
+	assert.Eventually(t, func() bool { return len(b.queue) == 1 }, time.Second, time.Millisecond)
+
+	b.Close()
+	close(unblock) // let dispatch return so run() reaches the done case and drains the queue.
+
+	select {
+	case err := <-queuedErrCh:
+		assert.ErrorIs(t, err, ErrBatcherClosed)
+	case <-time.After(time.Second):
+		t.Fatal("Predict call queued before Close never returned")
+	}
+}
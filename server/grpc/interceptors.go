@@ -0,0 +1,194 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/galafis/realtime-ml-serving-api/api/proto"
+	"github.com/galafis/realtime-ml-serving-api/server/authz"
+	"github.com/galafis/realtime-ml-serving-api/server/middleware"
+)
+
+// predictMethod/predictStreamMethod are the only RPCs the HTTP router
+// protects with middleware.RateLimiter/middleware.CircuitBreaker (via
+// POST /predict); ListModels, like GET /models, is left unprotected on
+// both transports.
+const (
+	predictMethod       = "/inference.InferenceService/Predict"
+	predictStreamMethod = "/inference.InferenceService/PredictStream"
+)
+
+// clientIPOf extracts the caller's IP from ctx's peer info, mirroring
+// gin.Context.ClientIP on the HTTP path.
+func clientIPOf(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func modelNameOf(req interface{}) string {
+	if r, ok := req.(*pb.PredictRequest); ok {
+		return r.GetModelName()
+	}
+	return ""
+}
+
+// peerIdentityOf returns the CN of ctx's client certificate, or "" if
+// the call isn't authenticated via mTLS, mirroring middleware.Authz's
+// "skip when there's no client cert" behavior on the HTTP path.
+func peerIdentityOf(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return ""
+	}
+	return tlsInfo.State.PeerCertificates[0].Subject.CommonName
+}
+
+// authorize enforces authorizer the same way middleware.Authz does:
+// skip entirely when the call carries no client certificate, otherwise
+// require the CN to be allowed to call modelName.
+func authorize(ctx context.Context, authorizer authz.Authorizer, modelName string) error {
+	identity := peerIdentityOf(ctx)
+	if identity == "" {
+		return nil
+	}
+	if !authorizer.Authorize(identity, modelName) {
+		return status.Errorf(codes.PermissionDenied, "client %s is not authorized for model %s", identity, modelName)
+	}
+	return nil
+}
+
+// unaryInterceptor enforces the same mTLS authorization, rate limit,
+// and circuit breaker that middleware.Authz/RateLimiter/CircuitBreaker
+// enforce on POST /predict, so a gRPC client can't bypass them just by
+// calling Predict directly instead of going through HTTP.
+func unaryInterceptor(rl *middleware.RateLimiter, cb *middleware.CircuitBreaker, authorizer authz.Authorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info.FullMethod != predictMethod {
+			return handler(ctx, req)
+		}
+
+		modelName := modelNameOf(req)
+
+		if err := authorize(ctx, authorizer, modelName); err != nil {
+			return nil, err
+		}
+		if !rl.Allow(clientIPOf(ctx), modelName) {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		if !cb.Allowed(modelName) {
+			return nil, status.Errorf(codes.Unavailable, "circuit breaker open for model %s", modelName)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		cb.Record(modelName, start, err != nil)
+
+		return resp, err
+	}
+}
+
+// streamInterceptor applies the same checks as unaryInterceptor to each
+// message of a PredictStream call, since every message carries its own
+// model_name, and records each message's outcome against the breaker
+// the same way unaryInterceptor records Predict's.
+func streamInterceptor(rl *middleware.RateLimiter, cb *middleware.CircuitBreaker, authorizer authz.Authorizer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if info.FullMethod != predictStreamMethod {
+			return handler(srv, ss)
+		}
+
+		gs := &guardedStream{
+			ServerStream: ss,
+			rl:           rl,
+			cb:           cb,
+			authorizer:   authorizer,
+			clientIP:     clientIPOf(ss.Context()),
+		}
+
+		err := handler(srv, gs)
+		// If the stream ended (e.g. the handler's Predict call failed)
+		// after RecvMsg admitted a message but before SendMsg reported
+		// its outcome, record it here so a failing model still trips
+		// the breaker even when the client never reads a response.
+		gs.record(err != nil)
+
+		return err
+	}
+}
+
+// guardedStream wraps a ServerStream so every received PredictStream
+// message is authorized, rate-limited, and breaker-checked before it
+// reaches the handler, the streaming equivalent of unaryInterceptor's
+// per-call checks, and so every message's outcome is recorded against
+// the breaker via SendMsg (or streamInterceptor's fallback above).
+type guardedStream struct {
+	grpc.ServerStream
+	rl         *middleware.RateLimiter
+	cb         *middleware.CircuitBreaker
+	authorizer authz.Authorizer
+	clientIP   string
+
+	lastModel    string
+	lastStart    time.Time
+	lastRecorded bool
+}
+
+func (s *guardedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	modelName := modelNameOf(m)
+
+	if err := authorize(s.Context(), s.authorizer, modelName); err != nil {
+		return err
+	}
+	if !s.rl.Allow(s.clientIP, modelName) {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	if !s.cb.Allowed(modelName) {
+		return status.Errorf(codes.Unavailable, "circuit breaker open for model %s", modelName)
+	}
+
+	s.lastModel = modelName
+	s.lastStart = time.Now()
+	s.lastRecorded = false
+
+	return nil
+}
+
+func (s *guardedStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	s.record(err != nil)
+	return err
+}
+
+// record reports the outcome of the most recently admitted message to
+// the breaker, once. Subsequent calls (the streamInterceptor fallback
+// after a successful SendMsg, or a rejected message that never reached
+// lastModel) are no-ops.
+func (s *guardedStream) record(isError bool) {
+	if s.lastModel == "" || s.lastRecorded {
+		return
+	}
+	s.cb.Record(s.lastModel, s.lastStart, isError)
+	s.lastRecorded = true
+}
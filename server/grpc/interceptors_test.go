@@ -0,0 +1,279 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/galafis/realtime-ml-serving-api/api/proto"
+	"github.com/galafis/realtime-ml-serving-api/server/authz"
+	"github.com/galafis/realtime-ml-serving-api/server/middleware"
+)
+
+// Author: Gabriel Demetrios Lafis
+
+// denyAllAuthorizer rejects every identity, for exercising the
+// "unauthorized CN" path without wiring up a real authz.yaml.
+type denyAllAuthorizer struct{}
+
+func (denyAllAuthorizer) Authorize(identity, modelName string) bool { return false }
+
+// mtlsContext builds a context carrying peer info the way a real mTLS
+// connection would, so authorize/peerIdentityOf see commonName as the
+// client certificate's CN.
+func mtlsContext(commonName string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000},
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: commonName}}},
+			},
+		},
+	})
+}
+
+func unaryInfo(method string) *grpc.UnaryServerInfo {
+	return &grpc.UnaryServerInfo{FullMethod: method}
+}
+
+func okHandler(ctx context.Context, req interface{}) (interface{}, error) {
+	return &pb.PredictResponse{Prediction: 1}, nil
+}
+
+func TestUnaryInterceptorRejectsUnauthorizedCN(t *testing.T) {
+	interceptor := unaryInterceptor(
+		middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1000, Burst: 1000}),
+		middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 1, LatencyBudget: time.Hour, CooldownPeriod: time.Hour}),
+		denyAllAuthorizer{},
+	)
+
+	_, err := interceptor(mtlsContext("untrusted-client"), &pb.PredictRequest{ModelName: "iris_classifier"}, unaryInfo(predictMethod), okHandler)
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.PermissionDenied, st.Code())
+}
+
+func TestUnaryInterceptorAllowsUnauthenticatedCallsThroughAuthz(t *testing.T) {
+	// No client certificate at all (plain TCP/TLS-without-mTLS) mirrors
+	// middleware.Authz's HTTP behavior: authorization is skipped, not
+	// denied, when there's no identity to check.
+	interceptor := unaryInterceptor(
+		middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1000, Burst: 1000}),
+		middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 1, LatencyBudget: time.Hour, CooldownPeriod: time.Hour}),
+		denyAllAuthorizer{},
+	)
+
+	resp, err := interceptor(context.Background(), &pb.PredictRequest{ModelName: "iris_classifier"}, unaryInfo(predictMethod), okHandler)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
+func TestUnaryInterceptorRejectsOverRateLimit(t *testing.T) {
+	interceptor := unaryInterceptor(
+		middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1, Burst: 0}),
+		middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 1, LatencyBudget: time.Hour, CooldownPeriod: time.Hour}),
+		authz.AllowAll{},
+	)
+
+	_, err := interceptor(mtlsContext("trusted-client"), &pb.PredictRequest{ModelName: "iris_classifier"}, unaryInfo(predictMethod), okHandler)
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+}
+
+func TestUnaryInterceptorRejectsWhileBreakerOpen(t *testing.T) {
+	cb := middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 0, LatencyBudget: time.Hour, CooldownPeriod: time.Hour})
+	cb.Record("iris_classifier", time.Now(), true) // trips immediately: ErrorRateThreshold 0 means any error opens it
+
+	interceptor := unaryInterceptor(
+		middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1000, Burst: 1000}),
+		cb,
+		authz.AllowAll{},
+	)
+
+	_, err := interceptor(mtlsContext("trusted-client"), &pb.PredictRequest{ModelName: "iris_classifier"}, unaryInfo(predictMethod), okHandler)
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Unavailable, st.Code())
+}
+
+func TestUnaryInterceptorRecordsOutcomeAgainstBreaker(t *testing.T) {
+	cb := middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 0.5, LatencyBudget: time.Hour, CooldownPeriod: time.Hour})
+	interceptor := unaryInterceptor(
+		middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1000, Burst: 1000}),
+		cb,
+		authz.AllowAll{},
+	)
+
+	failingHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("model exploded")
+	}
+
+	_, err := interceptor(mtlsContext("trusted-client"), &pb.PredictRequest{ModelName: "iris_classifier"}, unaryInfo(predictMethod), failingHandler)
+	assert.Error(t, err)
+
+	assert.False(t, cb.Allowed("iris_classifier"))
+}
+
+func TestUnaryInterceptorIgnoresUnrelatedMethods(t *testing.T) {
+	interceptor := unaryInterceptor(
+		middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 0, Burst: 0}),
+		middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 0, LatencyBudget: time.Hour, CooldownPeriod: time.Hour}),
+		denyAllAuthorizer{},
+	)
+
+	resp, err := interceptor(mtlsContext("untrusted-client"), &pb.ListModelsRequest{}, unaryInfo("/inference.InferenceService/ListModels"), okHandler)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
+// fakeServerStream is the minimal grpc.ServerStream a test needs:
+// guardedStream only ever calls Context, RecvMsg, and SendMsg on the
+// stream it wraps.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	recvErr error
+	sendErr error
+}
+
+func (f *fakeServerStream) Context() context.Context    { return f.ctx }
+func (f *fakeServerStream) RecvMsg(m interface{}) error { return f.recvErr }
+func (f *fakeServerStream) SendMsg(m interface{}) error { return f.sendErr }
+
+func TestGuardedStreamRecvMsgRejectsUnauthorizedCN(t *testing.T) {
+	gs := &guardedStream{
+		ServerStream: &fakeServerStream{ctx: mtlsContext("untrusted-client")},
+		rl:           middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1000, Burst: 1000}),
+		cb:           middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 1, LatencyBudget: time.Hour, CooldownPeriod: time.Hour}),
+		authorizer:   denyAllAuthorizer{},
+		clientIP:     "10.0.0.1",
+	}
+
+	err := gs.RecvMsg(&pb.PredictRequest{ModelName: "iris_classifier"})
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.PermissionDenied, st.Code())
+	// An authorization rejection never admits the message, so there's
+	// nothing for a later SendMsg/fallback to record against the breaker.
+	assert.Empty(t, gs.lastModel)
+}
+
+func TestGuardedStreamRecvMsgRejectsOverRateLimit(t *testing.T) {
+	gs := &guardedStream{
+		ServerStream: &fakeServerStream{ctx: mtlsContext("trusted-client")},
+		rl:           middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1, Burst: 0}),
+		cb:           middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 1, LatencyBudget: time.Hour, CooldownPeriod: time.Hour}),
+		authorizer:   authz.AllowAll{},
+		clientIP:     "10.0.0.1",
+	}
+
+	err := gs.RecvMsg(&pb.PredictRequest{ModelName: "iris_classifier"})
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+}
+
+func TestGuardedStreamRecvMsgRejectsWhileBreakerOpen(t *testing.T) {
+	cb := middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 0, LatencyBudget: time.Hour, CooldownPeriod: time.Hour})
+	cb.Record("iris_classifier", time.Now(), true)
+
+	gs := &guardedStream{
+		ServerStream: &fakeServerStream{ctx: mtlsContext("trusted-client")},
+		rl:           middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1000, Burst: 1000}),
+		cb:           cb,
+		authorizer:   authz.AllowAll{},
+		clientIP:     "10.0.0.1",
+	}
+
+	err := gs.RecvMsg(&pb.PredictRequest{ModelName: "iris_classifier"})
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Unavailable, st.Code())
+}
+
+func TestGuardedStreamRecordsEachAdmittedMessageOnceViaSendMsg(t *testing.T) {
+	cb := middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 0.5, LatencyBudget: time.Hour, CooldownPeriod: time.Hour})
+	gs := &guardedStream{
+		ServerStream: &fakeServerStream{ctx: mtlsContext("trusted-client")},
+		rl:           middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1000, Burst: 1000}),
+		cb:           cb,
+		authorizer:   authz.AllowAll{},
+		clientIP:     "10.0.0.1",
+	}
+
+	assert.NoError(t, gs.RecvMsg(&pb.PredictRequest{ModelName: "iris_classifier"}))
+	assert.False(t, gs.lastRecorded)
+
+	assert.NoError(t, gs.SendMsg(&pb.PredictResponse{}))
+	assert.True(t, gs.lastRecorded)
+
+	// streamInterceptor's post-handler fallback calls record
+	// unconditionally; once SendMsg already recorded this message, it
+	// must be a no-op rather than counting it twice.
+	gs.record(true)
+	assert.True(t, gs.lastRecorded)
+	assert.True(t, cb.Allowed("iris_classifier"))
+}
+
+func TestGuardedStreamFallbackRecordsWhenSendMsgNeverReached(t *testing.T) {
+	cb := middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 0.5, LatencyBudget: time.Hour, CooldownPeriod: time.Hour})
+	gs := &guardedStream{
+		ServerStream: &fakeServerStream{ctx: mtlsContext("trusted-client")},
+		rl:           middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 1000, Burst: 1000}),
+		cb:           cb,
+		authorizer:   authz.AllowAll{},
+		clientIP:     "10.0.0.1",
+	}
+
+	// The handler admits the message (RecvMsg succeeds) then fails
+	// before ever calling SendMsg, e.g. because Predict returned an
+	// error. streamInterceptor's fallback (gs.record after handler
+	// returns) is the only thing that reports this outcome.
+	assert.NoError(t, gs.RecvMsg(&pb.PredictRequest{ModelName: "iris_classifier"}))
+	assert.False(t, gs.lastRecorded)
+
+	gs.record(true)
+
+	assert.True(t, gs.lastRecorded)
+	assert.False(t, cb.Allowed("iris_classifier"))
+}
+
+func TestStreamInterceptorIgnoresUnrelatedMethods(t *testing.T) {
+	interceptor := streamInterceptor(
+		middleware.NewRateLimiter(middleware.RateLimiterConfig{RPS: 0, Burst: 0}),
+		middleware.NewCircuitBreaker(middleware.CircuitBreakerConfig{WindowBuckets: 10, ErrorRateThreshold: 0, LatencyBudget: time.Hour, CooldownPeriod: time.Hour}),
+		denyAllAuthorizer{},
+	)
+
+	called := false
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		called = true
+		return nil
+	}
+
+	err := interceptor(nil, &fakeServerStream{ctx: mtlsContext("untrusted-client")}, &grpc.StreamServerInfo{FullMethod: "/inference.InferenceService/ListModels"}, handler)
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
@@ -0,0 +1,166 @@
+// Package grpc exposes the same predict/list-models/health surface as
+// the HTTP router in server/main.go, on top of the shared
+// service.PredictionService. Stubs are generated from
+// api/proto/inference.proto (see api/proto/generate.go).
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	pb "github.com/galafis/realtime-ml-serving-api/api/proto"
+	"github.com/galafis/realtime-ml-serving-api/server/authz"
+	"github.com/galafis/realtime-ml-serving-api/server/middleware"
+	"github.com/galafis/realtime-ml-serving-api/server/service"
+)
+
+// Server implements pb.InferenceServiceServer on top of a shared
+// PredictionService.
+type Server struct {
+	pb.UnimplementedInferenceServiceServer
+
+	svc        *service.PredictionService
+	grpcServer *grpc.Server
+	health     *health.Server
+}
+
+// New builds a Server and registers it, the gRPC health service, and
+// reflection on a fresh *grpc.Server. Predict and PredictStream go
+// through the same TLS/mTLS config, rate limiter, circuit breaker, and
+// authorizer as the HTTP server, so the gRPC surface gets the same
+// protections as POST /predict regardless of transport. tlsConfig may
+// be nil, matching buildTLSConfig's "plain HTTP" case in main.go.
+func New(svc *service.PredictionService, tlsConfig *tls.Config, rateLimiter *middleware.RateLimiter, circuitBreaker *middleware.CircuitBreaker, authorizer authz.Authorizer) *Server {
+	healthSrv := health.NewServer()
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptor(rateLimiter, circuitBreaker, authorizer)),
+		grpc.ChainStreamInterceptor(streamInterceptor(rateLimiter, circuitBreaker, authorizer)),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	grpcSrv := grpc.NewServer(opts...)
+
+	s := &Server{svc: svc, grpcServer: grpcSrv, health: healthSrv}
+
+	pb.RegisterInferenceServiceServer(grpcSrv, s)
+	healthpb.RegisterHealthServer(grpcSrv, healthSrv)
+	reflection.Register(grpcSrv)
+
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	return s
+}
+
+// Serve listens on addr and blocks until the server stops.
+func (s *Server) Serve(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.grpcServer.Serve(lis)
+}
+
+// GracefulStop stops accepting new RPCs and waits for in-flight ones to
+// finish, mirroring the HTTP server's Shutdown semantics in main().
+// Callers that need a deadline on this (an open PredictStream blocks it
+// indefinitely otherwise) should race it against a timer and fall back
+// to Stop.
+func (s *Server) GracefulStop() {
+	s.health.Shutdown()
+	s.grpcServer.GracefulStop()
+}
+
+// Stop aborts every in-flight RPC immediately, for callers that gave
+// GracefulStop a deadline and it didn't finish in time.
+func (s *Server) Stop() {
+	s.health.Shutdown()
+	s.grpcServer.Stop()
+}
+
+// Predict implements pb.InferenceServiceServer.
+func (s *Server) Predict(ctx context.Context, req *pb.PredictRequest) (*pb.PredictResponse, error) {
+	result, err := s.svc.Predict(ctx, service.PredictionRequest{
+		ModelName:    req.GetModelName(),
+		ModelVersion: req.GetModelVersion(),
+		Features:     req.GetFeatures(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toPredictResponse(result), nil
+}
+
+// ListModels implements pb.InferenceServiceServer, mirroring the HTTP
+// GET /models handler's use of the model registry behind svc.
+func (s *Server) ListModels(ctx context.Context, req *pb.ListModelsRequest) (*pb.ListModelsResponse, error) {
+	loaded := s.svc.ListModels()
+
+	models := make([]*pb.ListModelsResponse_Model, 0, len(loaded))
+	for _, m := range loaded {
+		models = append(models, &pb.ListModelsResponse_Model{
+			Name:    m.Name(),
+			Version: m.Version(),
+			Status:  "active",
+		})
+	}
+
+	return &pb.ListModelsResponse{Models: models}, nil
+}
+
+// PredictStream implements pb.InferenceServiceServer's bidi-streaming RPC
+// for high-throughput clients.
+func (s *Server) PredictStream(stream pb.InferenceService_PredictStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result, err := s.svc.Predict(stream.Context(), service.PredictionRequest{
+			ModelName:    req.GetModelName(),
+			ModelVersion: req.GetModelVersion(),
+			Features:     req.GetFeatures(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(toPredictResponse(result)); err != nil {
+			return err
+		}
+	}
+}
+
+func toPredictResponse(result *service.PredictionResponse) *pb.PredictResponse {
+	// A cache hit is decoded from JSON, so its class arrives as a
+	// float64 rather than the int a fresh prediction carries.
+	var prediction int64
+	switch p := result.Prediction.(type) {
+	case int:
+		prediction = int64(p)
+	case float64:
+		prediction = int64(p)
+	}
+
+	return &pb.PredictResponse{
+		Prediction:  prediction,
+		Probability: result.Probability,
+		LatencyMs:   result.LatencyMs,
+		CacheHit:    result.CacheHit,
+	}
+}
@@ -2,41 +2,98 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/galafis/realtime-ml-serving-api/server/authz"
+	grpcserver "github.com/galafis/realtime-ml-serving-api/server/grpc"
+	"github.com/galafis/realtime-ml-serving-api/server/middleware"
+	"github.com/galafis/realtime-ml-serving-api/server/registry"
+	"github.com/galafis/realtime-ml-serving-api/server/service"
+	"github.com/galafis/realtime-ml-serving-api/server/tlsutil"
+	"github.com/galafis/realtime-ml-serving-api/server/tracing"
 )
 
 // Author: Gabriel Demetrios Lafis
 
+var tracer = otel.Tracer(tracing.ServiceName)
+
 var (
-	ctx         = context.Background()
 	redisClient *redis.Client
+
+	// modelRegistry is the single source of truth for loaded models; it
+	// is swapped atomically on every POST /models/reload.
+	modelRegistry *registry.Registry
+
+	// predictionService backs both the HTTP predict handler below and
+	// the gRPC server in server/grpc, so there is a single prediction
+	// code path regardless of transport.
+	predictionService *service.PredictionService
+)
+
+// Prometheus metrics. Buckets are tuned for sub-millisecond to 100ms
+// serving latencies so histogram_quantile gives meaningful p50/p95/p99.
+var (
+	requestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ml_api_requests_total",
+			Help: "Total number of prediction requests processed",
+		},
+		[]string{"model_name", "model_version", "status", "cache_hit"},
+	)
+
+	predictionLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ml_api_prediction_latency_seconds",
+			Help:    "Prediction request latency in seconds",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25},
+		},
+		[]string{"model_name", "model_version"},
+	)
+
+	inFlightRequests = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ml_api_in_flight_requests",
+			Help: "Number of requests currently being served",
+		},
+		[]string{"path"},
+	)
 )
 
 // PredictionRequest represents an incoming prediction request
 type PredictionRequest struct {
-	ModelName string    `json:"model_name" binding:"required"`
-	Features  []float64 `json:"features" binding:"required"`
-	ModelVersion string `json:"model_version,omitempty"`
+	ModelName    string    `json:"model_name" binding:"required"`
+	Features     []float64 `json:"features" binding:"required"`
+	ModelVersion string    `json:"model_version,omitempty"`
 }
 
 // PredictionResponse represents the prediction result
 type PredictionResponse struct {
-	Prediction  interface{} `json:"prediction"`
-	Probability float64     `json:"probability,omitempty"`
-	ModelName   string      `json:"model_name"`
-	ModelVersion string     `json:"model_version"`
-	Latency     float64     `json:"latency_ms"`
-	CacheHit    bool        `json:"cache_hit"`
+	Prediction   interface{} `json:"prediction"`
+	Probability  float64     `json:"probability,omitempty"`
+	ModelName    string      `json:"model_name"`
+	ModelVersion string      `json:"model_version"`
+	Latency      float64     `json:"latency_ms"`
+	CacheHit     bool        `json:"cache_hit"`
 }
 
 // HealthResponse represents health check response
@@ -55,15 +112,32 @@ func init() {
 	})
 
 	// Test Redis connection
-	_, err := redisClient.Ping(ctx).Result()
+	_, err := redisClient.Ping(context.Background()).Result()
 	if err != nil {
 		log.Printf("Warning: Redis connection failed: %v", err)
 	} else {
 		log.Println("Redis connected successfully")
 	}
+
+	modelRegistry = registry.New(getEnv("MODELS_DIR", "./models"))
+	if _, err := modelRegistry.Load(); err != nil {
+		log.Printf("Warning: failed to load models: %v", err)
+	}
+
+	predictionService = service.NewPredictionService(redisClient, modelRegistry)
 }
 
 func main() {
+	shutdownTracing, err := tracing.Init(context.Background(), getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""))
+	if err != nil {
+		log.Fatalf("failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Printf("Warning: tracing shutdown failed: %v", err)
+		}
+	}()
+
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -71,32 +145,63 @@ func main() {
 	router := gin.Default()
 
 	// Middleware
+	router.Use(otelgin.Middleware(tracing.ServiceName))
 	router.Use(corsMiddleware())
 	router.Use(metricsMiddleware())
 
+	rateLimiter := middleware.NewRateLimiter(middleware.RateLimiterConfigFromEnv())
+	circuitBreaker := middleware.NewCircuitBreaker(middleware.CircuitBreakerConfigFromEnv())
+	authorizer := loadAuthorizer()
+
 	// Routes
 	router.GET("/health", healthCheck)
-	router.POST("/predict", predict)
+	router.POST("/predict", rateLimiter.Middleware(), circuitBreaker.Middleware(), middleware.Authz(authorizer), predict)
 	router.GET("/models", listModels)
-	router.GET("/metrics", getMetrics)
+	router.POST("/models/reload", reloadModels)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/stats", getMetrics)
+
+	httpAddr := getEnv("HTTP_ADDR", ":8080")
+	grpcAddr := getEnv("GRPC_ADDR", ":9090")
+
+	// tlsConfig is shared by the HTTP and gRPC listeners so both enforce
+	// the same mTLS policy off a single CertReloader.
+	tlsConfig := buildTLSConfig()
 
 	// Server configuration
 	srv := &http.Server{
-		Addr:         ":8080",
+		Addr:         httpAddr,
 		Handler:      router,
+		TLSConfig:    tlsConfig,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}
 
-	// Start server in goroutine
+	grpcSrv := grpcserver.New(predictionService, tlsConfig, rateLimiter, circuitBreaker, authorizer)
+
+	// Start servers in goroutines
 	go func() {
-		log.Println("Starting ML Serving API on :8080")
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Starting ML Serving API (HTTP) on %s", httpAddr)
+
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed to start: %v", err)
 		}
 	}()
 
+	go func() {
+		log.Printf("Starting ML Serving API (gRPC) on %s", grpcAddr)
+		if err := grpcSrv.Serve(grpcAddr); err != nil {
+			log.Fatalf("gRPC server failed to start: %v", err)
+		}
+	}()
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -111,6 +216,23 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// GracefulStop has no deadline of its own and would otherwise block
+	// on an open PredictStream forever; give it its own 5s budget (on
+	// top of the HTTP shutdown above, not shared with it) and fall back
+	// to Stop if it doesn't finish in time.
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcSrv.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	select {
+	case <-grpcStopped:
+	case <-time.After(5 * time.Second):
+		log.Println("gRPC server did not shut down gracefully in time, forcing stop")
+		grpcSrv.Stop()
+	}
+
 	log.Println("Server exited")
 }
 
@@ -122,81 +244,75 @@ func healthCheck(c *gin.Context) {
 	})
 }
 
+// predict is a thin Gin adapter over predictionService.Predict, which is
+// shared with the gRPC server in server/grpc.
 func predict(c *gin.Context) {
-	startTime := time.Now()
-
 	var req PredictionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Check cache
-	cacheKey := fmt.Sprintf("pred:%s:%v", req.ModelName, req.Features)
-	cachedResult, err := redisClient.Get(ctx, cacheKey).Result()
-	
-	var response PredictionResponse
-	cacheHit := false
+	ctx, span := tracer.Start(c.Request.Context(), "http.predict",
+		trace.WithAttributes(attribute.Int("feature.count", len(req.Features))),
+	)
+	defer span.End()
 
-	if err == nil {
-		// Cache hit
-		json.Unmarshal([]byte(cachedResult), &response)
-		cacheHit = true
-	} else {
-		// Cache miss - perform prediction
-		prediction, prob := makePrediction(req.Features)
-		
-		response = PredictionResponse{
-			Prediction:   prediction,
-			Probability:  prob,
-			ModelName:    req.ModelName,
-			ModelVersion: "1.0.0",
-			CacheHit:     false,
+	result, err := predictionService.Predict(ctx, service.PredictionRequest{
+		ModelName:    req.ModelName,
+		ModelVersion: req.ModelVersion,
+		Features:     req.Features,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		var validationErr *service.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, registry.ErrModelNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
+		return
+	}
 
-		// Store in cache
-		responseJSON, _ := json.Marshal(response)
-		redisClient.Set(ctx, cacheKey, responseJSON, 5*time.Minute)
+	span.SetAttributes(
+		attribute.String("model.name", result.ModelName),
+		attribute.String("model.version", result.ModelVersion),
+		attribute.Bool("cache.hit", result.CacheHit),
+	)
+
+	response := PredictionResponse{
+		Prediction:   result.Prediction,
+		Probability:  result.Probability,
+		ModelName:    result.ModelName,
+		ModelVersion: result.ModelVersion,
+		Latency:      result.LatencyMs,
+		CacheHit:     result.CacheHit,
 	}
 
-	// Calculate latency
-	latency := time.Since(startTime).Milliseconds()
-	response.Latency = float64(latency)
-	response.CacheHit = cacheHit
+	// Surface labels to metricsMiddleware so it can record per-model counters
+	// and histogram observations after c.Next() returns.
+	c.Set("model_name", response.ModelName)
+	c.Set("model_version", response.ModelVersion)
+	c.Set("cache_hit", response.CacheHit)
 
 	c.JSON(http.StatusOK, response)
 }
 
-func makePrediction(features []float64) (interface{}, float64) {
-	// Simplified prediction logic
-	// In production, load actual model and make prediction
-	sum := 0.0
-	for _, f := range features {
-		sum += f
-	}
-	
-	prediction := 0
-	if sum > 10.0 {
-		prediction = 1
-	}
-	
-	probability := 0.85
-	
-	return prediction, probability
-}
-
 func listModels(c *gin.Context) {
-	models := []map[string]interface{}{
-		{
-			"name":    "iris_classifier",
-			"version": "1.0.0",
-			"status":  "active",
-		},
-		{
-			"name":    "fraud_detector",
-			"version": "2.1.0",
+	loaded := predictionService.ListModels()
+
+	models := make([]map[string]interface{}, 0, len(loaded))
+	for _, m := range loaded {
+		models = append(models, map[string]interface{}{
+			"name":    m.Name(),
+			"version": m.Version(),
 			"status":  "active",
-		},
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -205,12 +321,24 @@ func listModels(c *gin.Context) {
 	})
 }
 
+// reloadModels hot-swaps the model registry from MODELS_DIR so operators
+// can push new model versions without restarting the process.
+func reloadModels(c *gin.Context) {
+	count, err := modelRegistry.Load()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"models_loaded": count})
+}
+
 func getMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
-		"total_requests":  1000,
-		"cache_hit_rate":  0.87,
-		"avg_latency_ms":  0.8,
-		"p99_latency_ms":  4.2,
+		"total_requests": 1000,
+		"cache_hit_rate": 0.87,
+		"avg_latency_ms": 0.8,
+		"p99_latency_ms": 4.2,
 	})
 }
 
@@ -232,13 +360,36 @@ func corsMiddleware() gin.HandlerFunc {
 func metricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
+
+		gauge := inFlightRequests.WithLabelValues(c.FullPath())
+		gauge.Inc()
+		defer gauge.Dec()
+
 		c.Next()
+
 		duration := time.Since(start)
-		
-		log.Printf("%s %s - %d - %v", 
-			c.Request.Method, 
-			c.Request.URL.Path, 
-			c.Writer.Status(), 
+
+		modelName, _ := c.Get("model_name")
+		modelVersion, _ := c.Get("model_version")
+		cacheHit, _ := c.Get("cache_hit")
+
+		modelNameStr, _ := modelName.(string)
+		modelVersionStr, _ := modelVersion.(string)
+		cacheHitStr := "false"
+		if hit, ok := cacheHit.(bool); ok && hit {
+			cacheHitStr = "true"
+		}
+
+		requestsTotal.WithLabelValues(modelNameStr, modelVersionStr, strconv.Itoa(c.Writer.Status()), cacheHitStr).Inc()
+
+		if modelNameStr != "" {
+			predictionLatency.WithLabelValues(modelNameStr, modelVersionStr).Observe(duration.Seconds())
+		}
+
+		log.Printf("%s %s - %d - %v",
+			c.Request.Method,
+			c.Request.URL.Path,
+			c.Writer.Status(),
 			duration)
 	}
 }
@@ -251,3 +402,52 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+// buildTLSConfig returns nil (plain HTTP) unless TLS_CERT_FILE is set.
+// The certificate is served through a CertReloader so it can rotate
+// without a restart; TLS_CLIENT_CA_FILE additionally enables mTLS.
+func buildTLSConfig() *tls.Config {
+	certFile := getEnv("TLS_CERT_FILE", "")
+	if certFile == "" {
+		return nil
+	}
+	keyFile := getEnv("TLS_KEY_FILE", "")
+
+	reloader, err := tlsutil.NewCertReloader(certFile, keyFile)
+	if err != nil {
+		log.Fatalf("failed to load TLS certificate: %v", err)
+	}
+
+	tlsConfig := &tls.Config{GetCertificate: reloader.GetCertificate}
+
+	if caFile := getEnv("TLS_CLIENT_CA_FILE", ""); caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			log.Fatalf("failed to read TLS client CA file: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			log.Fatalf("failed to parse TLS client CA file: %s", caFile)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig
+}
+
+// loadAuthorizer builds the model Authorizer from AUTHZ_CONFIG, or
+// authz.AllowAll when unset (e.g. mTLS disabled in local development).
+func loadAuthorizer() authz.Authorizer {
+	path := getEnv("AUTHZ_CONFIG", "")
+	if path == "" {
+		return authz.AllowAll{}
+	}
+
+	authorizer, err := authz.LoadStaticAuthorizer(path)
+	if err != nil {
+		log.Fatalf("failed to load authorization config: %v", err)
+	}
+	return authorizer
+}
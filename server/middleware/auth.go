@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/galafis/realtime-ml-serving-api/server/authz"
+)
+
+// Authz enforces per-client model authorization using the CN of the
+// client's mTLS certificate. Requests without a client certificate (TLS
+// disabled or TLS_CLIENT_CA_FILE unset) skip authorization entirely.
+func Authz(authorizer authz.Authorizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.TLS == nil || len(c.Request.TLS.PeerCertificates) == 0 {
+			c.Next()
+			return
+		}
+
+		identity := c.Request.TLS.PeerCertificates[0].Subject.CommonName
+
+		var body predictBody
+		_ = c.ShouldBindBodyWith(&body, binding.JSON)
+
+		if !authorizer.Authorize(identity, body.ModelName) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "client " + identity + " is not authorized for model " + body.ModelName,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
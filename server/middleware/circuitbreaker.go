@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var breakerState = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "ml_api_circuit_breaker_open",
+		Help: "1 if the per-model circuit breaker is currently open, 0 otherwise",
+	},
+	[]string{"model_name"},
+)
+
+// CircuitBreakerConfig configures the rolling error-rate/latency breaker.
+type CircuitBreakerConfig struct {
+	// WindowBuckets is the number of 1-second buckets kept in the
+	// rolling window (e.g. 10 for a 10s window).
+	WindowBuckets int
+	// ErrorRateThreshold trips the breaker once the window's error rate
+	// exceeds this fraction (0..1).
+	ErrorRateThreshold float64
+	// LatencyBudget trips the breaker once the window's p99 latency
+	// exceeds this duration.
+	LatencyBudget time.Duration
+	// CooldownPeriod is how long the breaker stays open before it lets
+	// traffic through again.
+	CooldownPeriod time.Duration
+}
+
+// CircuitBreakerConfigFromEnv reads CIRCUIT_BREAKER_* env vars, falling
+// back to a 10s window, 50% error rate threshold, 200ms p99 budget, and
+// a 30s cooldown.
+func CircuitBreakerConfigFromEnv() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		WindowBuckets:      envInt("CIRCUIT_BREAKER_WINDOW_SECONDS", 10),
+		ErrorRateThreshold: envFloat("CIRCUIT_BREAKER_ERROR_RATE", 0.5),
+		LatencyBudget:      envDuration("CIRCUIT_BREAKER_LATENCY_BUDGET", 200*time.Millisecond),
+		CooldownPeriod:     envDuration("CIRCUIT_BREAKER_COOLDOWN", 30*time.Second),
+	}
+}
+
+type secondBucket struct {
+	second    int64
+	requests  int
+	errors    int
+	latencies []time.Duration
+}
+
+// modelBreaker tracks a rolling window of 1-second buckets for a single
+// model and whether the breaker is currently open.
+type modelBreaker struct {
+	mu      sync.Mutex
+	buckets []secondBucket
+
+	open     bool
+	openedAt time.Time
+}
+
+func newModelBreaker(windowBuckets int) *modelBreaker {
+	return &modelBreaker{buckets: make([]secondBucket, windowBuckets)}
+}
+
+func (b *modelBreaker) bucketFor(now time.Time) *secondBucket {
+	second := now.Unix()
+	idx := int(second % int64(len(b.buckets)))
+	bucket := &b.buckets[idx]
+	if bucket.second != second {
+		*bucket = secondBucket{second: second}
+	}
+	return bucket
+}
+
+// record stores the outcome of one request and returns whether the
+// breaker should (re)open.
+func (b *modelBreaker) record(cfg CircuitBreakerConfig, now time.Time, isError bool, latency time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bucket := b.bucketFor(now)
+	bucket.requests++
+	if isError {
+		bucket.errors++
+	}
+	bucket.latencies = append(bucket.latencies, latency)
+
+	requests, errors, p99 := b.windowStats(cfg, now)
+	if requests == 0 {
+		return b.open
+	}
+
+	tripped := float64(errors)/float64(requests) > cfg.ErrorRateThreshold || p99 > cfg.LatencyBudget
+	if tripped {
+		b.open = true
+		b.openedAt = now
+	}
+	return b.open
+}
+
+// windowStats aggregates requests/errors/p99 latency across every
+// bucket still inside the rolling window.
+func (b *modelBreaker) windowStats(cfg CircuitBreakerConfig, now time.Time) (requests, errors int, p99 time.Duration) {
+	cutoff := now.Unix() - int64(cfg.WindowBuckets)
+
+	var allLatencies []time.Duration
+	for _, bucket := range b.buckets {
+		if bucket.second <= cutoff || bucket.second == 0 {
+			continue
+		}
+		requests += bucket.requests
+		errors += bucket.errors
+		allLatencies = append(allLatencies, bucket.latencies...)
+	}
+
+	if len(allLatencies) == 0 {
+		return requests, errors, 0
+	}
+
+	sort.Slice(allLatencies, func(i, j int) bool { return allLatencies[i] < allLatencies[j] })
+	idx := (len(allLatencies) * 99) / 100
+	if idx >= len(allLatencies) {
+		idx = len(allLatencies) - 1
+	}
+	return requests, errors, allLatencies[idx]
+}
+
+// allowed reports whether a request may proceed, transitioning an open
+// breaker back to closed once the cooldown has elapsed.
+func (b *modelBreaker) allowed(cfg CircuitBreakerConfig, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+	if now.Sub(b.openedAt) >= cfg.CooldownPeriod {
+		b.open = false
+		return true
+	}
+	return false
+}
+
+// CircuitBreaker is a per-model circuit breaker over rolling error rate
+// and p99 latency.
+type CircuitBreaker struct {
+	cfg      CircuitBreakerConfig
+	breakers sync.Map // model name -> *modelBreaker
+}
+
+// NewCircuitBreaker builds a CircuitBreaker using cfg for every model.
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{cfg: cfg}
+}
+
+func (cb *CircuitBreaker) breakerFor(modelName string) *modelBreaker {
+	if b, ok := cb.breakers.Load(modelName); ok {
+		return b.(*modelBreaker)
+	}
+	b := newModelBreaker(cb.cfg.WindowBuckets)
+	actual, _ := cb.breakers.LoadOrStore(modelName, b)
+	return actual.(*modelBreaker)
+}
+
+// Allowed reports whether a request for modelName may proceed right
+// now. It is the transport-agnostic check behind Middleware, reused by
+// the gRPC interceptors in server/grpc so Predict is protected the
+// same way regardless of transport.
+func (cb *CircuitBreaker) Allowed(modelName string) bool {
+	return cb.breakerFor(modelName).allowed(cb.cfg, time.Now())
+}
+
+// Record stores the outcome of a just-finished request for modelName
+// started at start, updates the open/closed Prometheus gauge, and
+// reports whether the breaker is now open.
+func (cb *CircuitBreaker) Record(modelName string, start time.Time, isError bool) bool {
+	isOpen := cb.breakerFor(modelName).record(cb.cfg, start, isError, time.Since(start))
+
+	state := 0.0
+	if isOpen {
+		state = 1.0
+	}
+	breakerState.WithLabelValues(modelName).Set(state)
+
+	return isOpen
+}
+
+// Middleware short-circuits POST /predict with a 503 while a model's
+// breaker is open, and otherwise records the outcome of the request to
+// decide whether it should trip.
+func (cb *CircuitBreaker) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body predictBody
+		_ = c.ShouldBindBodyWith(&body, binding.JSON)
+
+		start := time.Now()
+
+		if !cb.Allowed(body.ModelName) {
+			breakerState.WithLabelValues(body.ModelName).Set(1)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "circuit breaker open for model " + body.ModelName,
+			})
+			return
+		}
+
+		c.Next()
+
+		cb.Record(body.ModelName, start, c.Writer.Status() >= http.StatusInternalServerError)
+	}
+}
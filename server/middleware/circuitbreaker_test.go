@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// Author: Gabriel Demetrios Lafis
+
+func TestCircuitBreakerTripsOnErrorRate(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		WindowBuckets:      10,
+		ErrorRateThreshold: 0.5,
+		LatencyBudget:      time.Second,
+		CooldownPeriod:     time.Minute,
+	})
+
+	assert.True(t, cb.Allowed("fraud_detector"))
+
+	start := time.Now()
+	cb.Record("fraud_detector", start, true)
+	cb.Record("fraud_detector", start, true)
+	cb.Record("fraud_detector", start, false)
+
+	assert.False(t, cb.Allowed("fraud_detector"))
+}
+
+func TestCircuitBreakerTripsOnLatencyBudget(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		WindowBuckets:      10,
+		ErrorRateThreshold: 1, // only the latency budget should trip this one
+		LatencyBudget:      10 * time.Millisecond,
+		CooldownPeriod:     time.Minute,
+	})
+
+	slowStart := time.Now().Add(-50 * time.Millisecond)
+	cb.Record("fraud_detector", slowStart, false)
+
+	assert.False(t, cb.Allowed("fraud_detector"))
+}
+
+func TestCircuitBreakerClosesAfterCooldown(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		WindowBuckets:      10,
+		ErrorRateThreshold: 0.5,
+		LatencyBudget:      time.Second,
+		CooldownPeriod:     10 * time.Millisecond,
+	})
+
+	start := time.Now()
+	cb.Record("iris_classifier", start, true)
+	assert.False(t, cb.Allowed("iris_classifier"))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, cb.Allowed("iris_classifier"))
+}
+
+func TestCircuitBreakerMiddlewareRejectsWhenOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		WindowBuckets:      10,
+		ErrorRateThreshold: 0,
+		LatencyBudget:      time.Second,
+		CooldownPeriod:     time.Minute,
+	})
+
+	router := gin.New()
+	router.POST("/predict", cb.Middleware(), func(c *gin.Context) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"})
+	})
+
+	body := `{"model_name":"iris_classifier","features":[1,2,3]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/predict", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	req2 := httptest.NewRequest(http.MethodPost, "/predict", strings.NewReader(body))
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, req2)
+	assert.Equal(t, http.StatusServiceUnavailable, w2.Code)
+}
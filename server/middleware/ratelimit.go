@@ -0,0 +1,188 @@
+// Package middleware provides Gin middlewares for the predict route:
+// per-(client, model) rate limiting and a per-model circuit breaker.
+// Both middlewares peek the request body for model_name via
+// c.ShouldBindBodyWith, which Gin caches so the predict handler can
+// still bind it fresh afterwards.
+package middleware
+
+import (
+	"container/list"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+)
+
+// numLimiterShards trades lock contention against bookkeeping overhead
+// for the rate limiter's sharded map.
+const numLimiterShards = 32
+
+// limiterShardCapacity bounds how many (client, model) limiters each
+// shard keeps before evicting the least recently used one.
+const limiterShardCapacity = 2048
+
+type predictBody struct {
+	ModelName string `json:"model_name"`
+}
+
+var rateLimitRejections = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ml_api_rate_limit_rejections_total",
+		Help: "Requests rejected by the token-bucket rate limiter",
+	},
+	[]string{"model_name"},
+)
+
+// RateLimiterConfig configures the token-bucket rate limiter.
+type RateLimiterConfig struct {
+	RPS   float64
+	Burst int
+}
+
+// RateLimiterConfigFromEnv reads RATE_LIMIT_RPS / RATE_LIMIT_BURST,
+// falling back to 50 req/s with a burst of 100.
+func RateLimiterConfigFromEnv() RateLimiterConfig {
+	return RateLimiterConfig{
+		RPS:   envFloat("RATE_LIMIT_RPS", 50),
+		Burst: envInt("RATE_LIMIT_BURST", 100),
+	}
+}
+
+type limiterEntry struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+// limiterShard is an LRU-bounded bucket of per-key token-bucket
+// limiters, guarded by its own lock so unrelated keys never contend.
+type limiterShard struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func newLimiterShard() *limiterShard {
+	return &limiterShard{entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func (s *limiterShard) get(key string, cfg RateLimiterConfig) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		s.order.MoveToFront(el)
+		return el.Value.(*limiterEntry).limiter
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(cfg.RPS), cfg.Burst)
+	el := s.order.PushFront(&limiterEntry{key: key, limiter: limiter})
+	s.entries[key] = el
+
+	if s.order.Len() > limiterShardCapacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*limiterEntry).key)
+	}
+
+	return limiter
+}
+
+// RateLimiter is a token-bucket limiter keyed by (client IP, model
+// name), sharded to reduce lock contention under concurrent load.
+type RateLimiter struct {
+	cfg    RateLimiterConfig
+	shards []*limiterShard
+}
+
+// NewRateLimiter builds a RateLimiter with numLimiterShards independent
+// shards.
+func NewRateLimiter(cfg RateLimiterConfig) *RateLimiter {
+	rl := &RateLimiter{
+		cfg:    cfg,
+		shards: make([]*limiterShard, numLimiterShards),
+	}
+	for i := range rl.shards {
+		rl.shards[i] = newLimiterShard()
+	}
+	return rl
+}
+
+func (rl *RateLimiter) shardIndex(key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(rl.shards)))
+}
+
+// Allow reports whether a request for (clientIP, modelName) may proceed.
+func (rl *RateLimiter) Allow(clientIP, modelName string) bool {
+	key := clientIP + ":" + modelName
+	shard := rl.shards[rl.shardIndex(key)]
+
+	return shard.get(key, rl.cfg).Allow()
+}
+
+// Middleware enforces the rate limit for routes that accept a
+// model_name in their JSON body (namely POST /predict).
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body predictBody
+		_ = c.ShouldBindBodyWith(&body, binding.JSON)
+
+		if !rl.Allow(c.ClientIP(), body.ModelName) {
+			retryAfter := 1
+			if rl.cfg.RPS > 0 {
+				retryAfter = int(1/rl.cfg.RPS) + 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			rateLimitRejections.WithLabelValues(body.ModelName).Inc()
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func envFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func envInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
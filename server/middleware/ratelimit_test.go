@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// Author: Gabriel Demetrios Lafis
+
+func TestRateLimiterAllow(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterConfig{RPS: 1, Burst: 2})
+
+	assert.True(t, rl.Allow("10.0.0.1", "iris_classifier"))
+	assert.True(t, rl.Allow("10.0.0.1", "iris_classifier"))
+	assert.False(t, rl.Allow("10.0.0.1", "iris_classifier"))
+
+	// A different (client, model) key gets its own independent bucket.
+	assert.True(t, rl.Allow("10.0.0.2", "iris_classifier"))
+	assert.True(t, rl.Allow("10.0.0.1", "fraud_detector"))
+}
+
+func TestLimiterShardEvictsLeastRecentlyUsed(t *testing.T) {
+	shard := newLimiterShard()
+	cfg := RateLimiterConfig{RPS: 1, Burst: 1}
+
+	for i := 0; i < limiterShardCapacity; i++ {
+		shard.get(strconv.Itoa(i), cfg)
+	}
+	assert.Equal(t, limiterShardCapacity, shard.order.Len())
+
+	// One more key evicts the oldest ("0").
+	shard.get("overflow", cfg)
+	assert.Equal(t, limiterShardCapacity, shard.order.Len())
+
+	_, ok := shard.entries["0"]
+	assert.False(t, ok)
+	_, ok = shard.entries["overflow"]
+	assert.True(t, ok)
+}
+
+func TestRateLimiterMiddlewareRejects(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rl := NewRateLimiter(RateLimiterConfig{RPS: 1, Burst: 1})
+	router := gin.New()
+	router.POST("/predict", rl.Middleware(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	body := `{"model_name":"iris_classifier","features":[1,2,3]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/predict", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req2 := httptest.NewRequest(http.MethodPost, "/predict", strings.NewReader(body))
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, req2)
+	assert.Equal(t, http.StatusTooManyRequests, w2.Code)
+	assert.NotEmpty(t, w2.Header().Get("Retry-After"))
+}
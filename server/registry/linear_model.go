@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// linearModelFile is the on-disk JSON representation of the pure-Go
+// fallback model: a logistic-regression-style weight vector and bias.
+type linearModelFile struct {
+	Name    string    `json:"name"`
+	Version string    `json:"version"`
+	Weights []float64 `json:"weights"`
+	Bias    float64   `json:"bias"`
+}
+
+// LinearModel is a pure-Go linear/logistic model, used when no ONNX
+// runtime is available or as a lightweight default.
+type LinearModel struct {
+	name    string
+	version string
+	weights []float64
+	bias    float64
+}
+
+// NewLinearModel builds a LinearModel from an already-parsed weight
+// vector and bias.
+func NewLinearModel(name, version string, weights []float64, bias float64) *LinearModel {
+	return &LinearModel{name: name, version: version, weights: weights, bias: bias}
+}
+
+func loadLinearModel(path string) (Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file linearModelFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid linear model file: %w", err)
+	}
+
+	if file.Name == "" || file.Version == "" {
+		return nil, fmt.Errorf("linear model file missing name/version")
+	}
+
+	return NewLinearModel(file.Name, file.Version, file.Weights, file.Bias), nil
+}
+
+func (m *LinearModel) Name() string    { return m.name }
+func (m *LinearModel) Version() string { return m.version }
+func (m *LinearModel) InputShape() int { return len(m.weights) }
+
+// Predict scores a single feature vector via PredictBatch.
+func (m *LinearModel) Predict(features []float64) (interface{}, float64, error) {
+	predictions, probabilities, err := m.PredictBatch([][]float64{features})
+	if err != nil {
+		return nil, 0, err
+	}
+	return predictions[0], probabilities[0], nil
+}
+
+// PredictBatch applies the linear model to each feature vector and
+// squashes the result through a sigmoid to produce a probability,
+// thresholding at 0.5 for the class.
+func (m *LinearModel) PredictBatch(batch [][]float64) ([]interface{}, []float64, error) {
+	predictions := make([]interface{}, len(batch))
+	probabilities := make([]float64, len(batch))
+
+	for i, features := range batch {
+		if len(features) != len(m.weights) {
+			return nil, nil, fmt.Errorf("linear model %s@%s: expected %d features, got %d", m.name, m.version, len(m.weights), len(features))
+		}
+
+		sum := m.bias
+		for j, f := range features {
+			sum += f * m.weights[j]
+		}
+
+		probability := 1 / (1 + math.Exp(-sum))
+		prediction := 0
+		if probability > 0.5 {
+			prediction = 1
+		}
+
+		predictions[i] = prediction
+		probabilities[i] = probability
+	}
+
+	return predictions, probabilities, nil
+}
@@ -0,0 +1,45 @@
+//go:build onnx
+
+package registry
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// parseONNXFileName expects a file name of the form "name@version.onnx".
+func parseONNXFileName(path string) (name, version string, err error) {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name, version, ok := strings.Cut(base, "@")
+	if !ok {
+		return "", "", fmt.Errorf("onnx model file name must be name@version.onnx, got %s", filepath.Base(path))
+	}
+	return name, version, nil
+}
+
+// loadONNXModel validates that path is a loadable onnx model with a
+// single output, then refuses to load it.
+//
+// Decoding an onnxruntime output tensor into (prediction, probability)
+// is output-shape- and dtype-specific per model, and that decoding is
+// not implemented, so every .onnx model is rejected here, at load time,
+// rather than loading and then failing on its first prediction.
+func loadONNXModel(path string) (Model, error) {
+	name, version, err := parseONNXFileName(path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, outputInfo, err := ort.GetInputOutputInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading onnx model metadata: %w", err)
+	}
+	if len(outputInfo) != 1 {
+		return nil, fmt.Errorf("onnx model %s@%s: only single-output models are supported, got %d outputs", name, version, len(outputInfo))
+	}
+
+	return nil, fmt.Errorf("registry: onnx model %s@%s: output decoding not implemented yet, refusing to load %s", name, version, path)
+}
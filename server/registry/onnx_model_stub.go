@@ -0,0 +1,12 @@
+//go:build !onnx
+
+package registry
+
+import "fmt"
+
+// loadONNXModel is stubbed out unless the binary is built with
+// `-tags onnx` and the onnxruntime shared library available, so the
+// default build doesn't pick up a native dependency.
+func loadONNXModel(path string) (Model, error) {
+	return nil, fmt.Errorf("registry: %s requires building with -tags onnx", path)
+}
@@ -0,0 +1,252 @@
+// Package registry loads versioned models from disk and serves them to
+// the prediction service. Models are swapped atomically so
+// POST /models/reload never blocks an in-flight prediction; see Load
+// for the narrow window in which a request racing a reload gets
+// batcher.ErrBatcherClosed.
+//
+// ONNX is not actually served yet. loadONNXModel (onnx_model.go, built
+// with `-tags onnx`) validates that a .onnx file is readable and has a
+// single output, then deliberately refuses to load it: decoding an
+// onnxruntime output tensor into (prediction, probability) is
+// output-shape- and dtype-specific per model, and that decoding isn't
+// implemented. The default build (no `-tags onnx`) doesn't even link
+// onnxruntime_go and refuses every .onnx file outright. The only
+// backend that actually predicts is the pure-Go LinearModel loaded
+// from JSON.
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/galafis/realtime-ml-serving-api/server/batcher"
+)
+
+// ErrModelNotFound is returned when no model matches the requested
+// name/version pair.
+var ErrModelNotFound = errors.New("registry: model not found")
+
+// Model is implemented by every loadable model backend. The pure-Go
+// LinearModel is the only one; .onnx files are refused (see the
+// package doc).
+type Model interface {
+	// Predict runs inference over a single feature vector, returning the
+	// predicted class/value and its probability. Implementations define
+	// it in terms of PredictBatch with a single-element batch.
+	Predict(features []float64) (interface{}, float64, error)
+
+	// PredictBatch scores a batch of feature vectors in one call, for
+	// use by the batcher package.
+	PredictBatch(features [][]float64) ([]interface{}, []float64, error)
+
+	Name() string
+	Version() string
+	InputShape() int
+}
+
+// entry pairs a loaded model with the dedicated batcher.Batcher built
+// for that exact model instance. Both are stored and swapped together
+// so a reload can never leave a model served by a *Batcher still
+// closed over the model instance it replaced.
+type entry struct {
+	model   Model
+	batcher *batcher.Batcher
+}
+
+// Registry holds the currently active set of models, keyed by
+// "name@version". Readers always see a consistent snapshot via
+// atomic.Pointer, so Reload can hot-swap models without locking.
+type Registry struct {
+	modelsDir string
+	entries   atomic.Pointer[map[string]*entry]
+}
+
+// New builds an empty Registry rooted at modelsDir. Call Load to
+// populate it.
+func New(modelsDir string) *Registry {
+	r := &Registry{modelsDir: modelsDir}
+	empty := map[string]*entry{}
+	r.entries.Store(&empty)
+	return r
+}
+
+// Load walks modelsDir, loads every recognized model file, builds a
+// fresh batcher.Batcher for each one, and atomically swaps the
+// (model, batcher) pairs in together, closing every previously active
+// Batcher afterwards. A model reloaded under the same name@version
+// always gets a new Batcher wrapping the new model instance instead of
+// silently reusing one built for the model it replaced.
+//
+// This closes the old Batcher for every model on any reload, even
+// ones whose file didn't change, since Load has no way to tell an
+// unmodified model apart from a new version short of re-parsing and
+// diffing every file. A request that resolved its (model, batcher)
+// pair just before a reload and calls Predict just as the old Batcher
+// is closed gets ErrBatcherClosed instead of a result; callers should
+// treat that as retryable. That narrow race is the deliberate
+// trade-off for guaranteeing a reload never leaves a model served by
+// a Batcher closed over stale weights.
+func (r *Registry) Load() (int, error) {
+	loaded, err := loadModelsFromDir(r.modelsDir)
+	if err != nil {
+		return 0, err
+	}
+
+	entries := make(map[string]*entry, len(loaded))
+	for key, model := range loaded {
+		entries[key] = &entry{model: model, batcher: batcher.New(key, model, batcher.DefaultOptions())}
+	}
+
+	previous := r.entries.Swap(&entries)
+	if previous != nil {
+		for _, e := range *previous {
+			e.batcher.Close()
+		}
+	}
+
+	return len(entries), nil
+}
+
+// resolve looks up the entry for name and optional version in a single
+// atomic read, so the Model and *Batcher a caller gets back always
+// came from the same Load. An empty version resolves to the highest
+// version loaded for that name.
+func (r *Registry) resolve(name, version string) (*entry, bool) {
+	entries := *r.entries.Load()
+
+	if version != "" {
+		e, ok := entries[modelKey(name, version)]
+		return e, ok
+	}
+
+	var latest *entry
+	for k, e := range entries {
+		modelName, modelVersion, ok := splitKey(k)
+		if !ok || modelName != name {
+			continue
+		}
+		if latest == nil || compareVersions(modelVersion, latest.model.Version()) > 0 {
+			latest = e
+		}
+	}
+
+	if latest == nil {
+		return nil, false
+	}
+	return latest, true
+}
+
+// Get resolves a model by name and optional version. An empty version
+// resolves to the highest version loaded for that name.
+func (r *Registry) Get(name, version string) (Model, bool) {
+	e, ok := r.resolve(name, version)
+	if !ok {
+		return nil, false
+	}
+	return e.model, true
+}
+
+// Resolve resolves the same model Get would, plus the batcher.Batcher
+// built alongside it at Load time, in a single atomic read. Callers
+// that validate against the Model and then predict through its Batcher
+// should use this instead of a separate Get, so the pair they act on
+// is guaranteed to have come from the same Load even if a reload races
+// with the call.
+func (r *Registry) Resolve(name, version string) (Model, *batcher.Batcher, bool) {
+	e, ok := r.resolve(name, version)
+	if !ok {
+		return nil, nil, false
+	}
+	return e.model, e.batcher, true
+}
+
+// List returns every currently loaded model, for the GET /models
+// endpoint.
+func (r *Registry) List() []Model {
+	entries := *r.entries.Load()
+
+	list := make([]Model, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e.model)
+	}
+	return list
+}
+
+func modelKey(name, version string) string {
+	return name + "@" + version
+}
+
+func splitKey(key string) (name, version string, ok bool) {
+	name, version, found := strings.Cut(key, "@")
+	return name, version, found
+}
+
+// compareVersions compares dotted numeric versions (e.g. "2.1.0")
+// component by component, falling back to a plain string compare for
+// anything non-numeric.
+func compareVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aNum, bNum int
+		if i < len(aParts) {
+			aNum, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bNum, _ = strconv.Atoi(bParts[i])
+		}
+		if aNum != bNum {
+			return aNum - bNum
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+// loadModelsFromDir loads every *.json (pure-Go linear model) file
+// directly under dir, keyed by "name@version" taken from each model's
+// own metadata rather than the filename. *.onnx files are handed to
+// loadONNXModel, which always returns an error, so a dir holding one
+// fails to load as a whole.
+func loadModelsFromDir(dir string) (map[string]Model, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("registry: reading models dir %s: %w", dir, err)
+	}
+
+	models := make(map[string]Model, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		var model Model
+		var loadErr error
+
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json":
+			model, loadErr = loadLinearModel(path)
+		case ".onnx":
+			model, loadErr = loadONNXModel(path)
+		default:
+			continue
+		}
+
+		if loadErr != nil {
+			return nil, fmt.Errorf("registry: loading %s: %w", path, loadErr)
+		}
+
+		models[modelKey(model.Name(), model.Version())] = model
+	}
+
+	return models, nil
+}
@@ -0,0 +1,123 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Author: Gabriel Demetrios Lafis
+
+func writeModelFile(t *testing.T, dir, fileName string, model linearModelFile) {
+	t.Helper()
+
+	data, err := json.Marshal(model)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, fileName), data, 0644))
+}
+
+func TestRegistryLoadsValidJSONModel(t *testing.T) {
+	dir := t.TempDir()
+	writeModelFile(t, dir, "iris_classifier@1.0.0.json", linearModelFile{
+		Name: "iris_classifier", Version: "1.0.0", Weights: []float64{1, 1, 1}, Bias: 0,
+	})
+
+	reg := New(dir)
+	count, err := reg.Load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	model, ok := reg.Get("iris_classifier", "1.0.0")
+	assert.True(t, ok)
+	assert.Equal(t, "iris_classifier", model.Name())
+	assert.Equal(t, "1.0.0", model.Version())
+}
+
+func TestRegistryLoadRejectsMalformedJSON(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "broken@1.0.0.json"), []byte("{not valid json"), 0644))
+
+	reg := New(dir)
+	_, err := reg.Load()
+
+	assert.Error(t, err)
+}
+
+func TestRegistryLoadRejectsONNXModels(t *testing.T) {
+	// The default build has no onnxruntime linked in (see the package
+	// doc on this limitation); loadONNXModel's stub refuses every
+	// .onnx file outright regardless of its contents.
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "fraud_detector@1.0.0.onnx"), []byte("not a real onnx file"), 0644))
+
+	reg := New(dir)
+	_, err := reg.Load()
+
+	assert.Error(t, err)
+}
+
+func TestRegistryLoadSkipsUnrecognizedExtensions(t *testing.T) {
+	dir := t.TempDir()
+	writeModelFile(t, dir, "iris_classifier@1.0.0.json", linearModelFile{
+		Name: "iris_classifier", Version: "1.0.0", Weights: []float64{1, 1, 1}, Bias: 0,
+	})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.txt"), []byte("not a model"), 0644))
+
+	reg := New(dir)
+	count, err := reg.Load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestRegistryGetDefaultsToLatestVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeModelFile(t, dir, "iris_classifier@1.0.0.json", linearModelFile{
+		Name: "iris_classifier", Version: "1.0.0", Weights: []float64{1}, Bias: 0,
+	})
+	writeModelFile(t, dir, "iris_classifier@2.1.0.json", linearModelFile{
+		Name: "iris_classifier", Version: "2.1.0", Weights: []float64{1, 1}, Bias: 0,
+	})
+
+	reg := New(dir)
+	_, err := reg.Load()
+	assert.NoError(t, err)
+
+	model, ok := reg.Get("iris_classifier", "")
+	assert.True(t, ok)
+	assert.Equal(t, "2.1.0", model.Version())
+}
+
+func TestRegistryGetUnknownModelReturnsFalse(t *testing.T) {
+	reg := New(t.TempDir())
+	_, err := reg.Load()
+	assert.NoError(t, err)
+
+	_, ok := reg.Get("does_not_exist", "")
+	assert.False(t, ok)
+}
+
+func TestRegistryResolveReturnsMatchingBatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeModelFile(t, dir, "iris_classifier@1.0.0.json", linearModelFile{
+		Name: "iris_classifier", Version: "1.0.0", Weights: []float64{1, 1, 1}, Bias: 0,
+	})
+
+	reg := New(dir)
+	_, err := reg.Load()
+	assert.NoError(t, err)
+
+	model, modelBatcher, ok := reg.Resolve("iris_classifier", "1.0.0")
+	assert.True(t, ok)
+	assert.NotNil(t, modelBatcher)
+
+	prediction, _, err := modelBatcher.Predict(context.Background(), []float64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, model.Name(), "iris_classifier")
+	assert.NotNil(t, prediction)
+}
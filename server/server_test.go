@@ -4,15 +4,57 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/galafis/realtime-ml-serving-api/server/registry"
+	"github.com/galafis/realtime-ml-serving-api/server/service"
 )
 
 // Author: Gabriel Demetrios Lafis
 
+// TestMain seeds modelRegistry with the fixture models the handler tests
+// below expect, replacing the registry init() loaded from MODELS_DIR
+// (which won't exist in the test environment).
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "registry-fixtures")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeModel(dir, "iris_classifier", "1.0.0", []float64{1, 1, 1, 1}, 0)
+	writeModel(dir, "fraud_detector", "2.1.0", []float64{1, 1, 1, 1, 1}, 0)
+
+	modelRegistry = registry.New(dir)
+	if _, err := modelRegistry.Load(); err != nil {
+		panic(err)
+	}
+	predictionService = service.NewPredictionService(redisClient, modelRegistry)
+
+	os.Exit(m.Run())
+}
+
+func writeModel(dir, name, version string, weights []float64, bias float64) {
+	data, err := json.Marshal(map[string]interface{}{
+		"name":    name,
+		"version": version,
+		"weights": weights,
+		"bias":    bias,
+	})
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+"@"+version+".json"), data, 0644); err != nil {
+		panic(err)
+	}
+}
+
 func TestHealthCheck(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	
@@ -142,21 +184,3 @@ func TestCORSMiddleware(t *testing.T) {
 	assert.Contains(t, resp.Header().Get("Access-Control-Allow-Methods"), "GET")
 }
 
-func TestMakePrediction(t *testing.T) {
-	// Test prediction logic
-	t.Run("Sum less than 10", func(t *testing.T) {
-		features := []float64{1.0, 2.0, 3.0}
-		prediction, probability := makePrediction(features)
-		
-		assert.Equal(t, 0, prediction)
-		assert.Equal(t, 0.85, probability)
-	})
-	
-	t.Run("Sum greater than 10", func(t *testing.T) {
-		features := []float64{5.0, 5.0, 5.0}
-		prediction, probability := makePrediction(features)
-		
-		assert.Equal(t, 1, prediction)
-		assert.Equal(t, 0.85, probability)
-	})
-}
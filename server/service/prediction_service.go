@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/galafis/realtime-ml-serving-api/server/batcher"
+	"github.com/galafis/realtime-ml-serving-api/server/registry"
+	"github.com/galafis/realtime-ml-serving-api/server/tracing"
+)
+
+var tracer = otel.Tracer(tracing.ServiceName + "/service")
+
+// PredictionRequest is the transport-agnostic prediction input shared by
+// the HTTP and gRPC surfaces.
+type PredictionRequest struct {
+	ModelName    string
+	ModelVersion string
+	Features     []float64
+}
+
+// PredictionResponse is the transport-agnostic prediction result shared
+// by the HTTP and gRPC surfaces.
+type PredictionResponse struct {
+	Prediction   interface{}
+	Probability  float64
+	ModelName    string
+	ModelVersion string
+	LatencyMs    float64
+	CacheHit     bool
+}
+
+// ValidationError marks a request that failed validation (e.g. a
+// feature-vector length mismatch) so callers can map it to a 400
+// instead of a 500.
+type ValidationError struct {
+	msg string
+}
+
+func (e *ValidationError) Error() string { return e.msg }
+
+// PredictionService holds the prediction logic previously inlined in the
+// predict Gin handler, so both the HTTP router and the gRPC server in
+// server/grpc can call the same code path.
+type PredictionService struct {
+	redis    *redis.Client
+	registry *registry.Registry
+}
+
+// NewPredictionService builds a PredictionService backed by the given
+// Redis client and model registry.
+func NewPredictionService(redisClient *redis.Client, modelRegistry *registry.Registry) *PredictionService {
+	return &PredictionService{redis: redisClient, registry: modelRegistry}
+}
+
+// ListModels returns every currently loaded model, shared by the HTTP
+// GET /models handler and the gRPC ListModels RPC.
+func (s *PredictionService) ListModels() []registry.Model {
+	return s.registry.List()
+}
+
+// Predict resolves the requested model (defaulting to its latest
+// version), validates the feature vector against it, and returns a
+// cached result or runs the model and caches the outcome.
+func (s *PredictionService) Predict(ctx context.Context, req PredictionRequest) (*PredictionResponse, error) {
+	start := time.Now()
+
+	model, modelBatcher, ok := s.registry.Resolve(req.ModelName, req.ModelVersion)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", registry.ErrModelNotFound, req.ModelName)
+	}
+
+	if len(req.Features) != model.InputShape() {
+		return nil, &ValidationError{msg: fmt.Sprintf(
+			"model %s@%s expects %d features, got %d", model.Name(), model.Version(), model.InputShape(), len(req.Features),
+		)}
+	}
+
+	commonAttrs := trace.WithAttributes(
+		attribute.String("model.name", model.Name()),
+		attribute.String("model.version", model.Version()),
+		attribute.Int("feature.count", len(req.Features)),
+	)
+
+	cacheKey := fmt.Sprintf("pred:%s:%s:%v", model.Name(), model.Version(), req.Features)
+
+	getCtx, getSpan := tracer.Start(ctx, "cache.get", commonAttrs)
+	cached, err := s.redis.Get(getCtx, cacheKey).Result()
+	getSpan.SetAttributes(attribute.Bool("cache.hit", err == nil))
+	getSpan.End()
+
+	var resp PredictionResponse
+	cacheHit := false
+
+	if err == nil {
+		if jsonErr := json.Unmarshal([]byte(cached), &resp); jsonErr != nil {
+			return nil, jsonErr
+		}
+		cacheHit = true
+	} else {
+		inferCtx, inferSpan := tracer.Start(ctx, "model.infer", commonAttrs)
+		prediction, probability, predErr := modelBatcher.Predict(inferCtx, req.Features)
+		if errors.Is(predErr, batcher.ErrBatcherClosed) {
+			// The Batcher resolved above was closed by a registry
+			// reload landing in the narrow window between Resolve and
+			// Predict. Re-resolve once to pick up its replacement
+			// rather than surfacing a reload as a client-visible error.
+			// The replacement may be a different model version (e.g.
+			// "latest" moved during the reload), so re-validate the
+			// feature vector against it and recompute the cache key
+			// before using its result. If the replacement is closed as
+			// well (a second reload, or the same Batcher when the
+			// registry hasn't reloaded at all), its ErrBatcherClosed is
+			// returned as is.
+			if freshModel, freshBatcher, ok := s.registry.Resolve(req.ModelName, req.ModelVersion); ok {
+				if len(req.Features) != freshModel.InputShape() {
+					return nil, &ValidationError{msg: fmt.Sprintf(
+						"model %s@%s expects %d features, got %d", freshModel.Name(), freshModel.Version(), freshModel.InputShape(), len(req.Features),
+					)}
+				}
+				model = freshModel
+				cacheKey = fmt.Sprintf("pred:%s:%s:%v", model.Name(), model.Version(), req.Features)
+				commonAttrs = trace.WithAttributes(
+					attribute.String("model.name", model.Name()),
+					attribute.String("model.version", model.Version()),
+					attribute.Int("feature.count", len(req.Features)),
+				)
+				inferSpan.SetAttributes(
+					attribute.String("model.name", model.Name()),
+					attribute.String("model.version", model.Version()),
+				)
+				prediction, probability, predErr = freshBatcher.Predict(inferCtx, req.Features)
+			} else {
+				predErr = fmt.Errorf("%w: %s", registry.ErrModelNotFound, req.ModelName)
+			}
+		}
+		if predErr != nil {
+			inferSpan.RecordError(predErr)
+			inferSpan.SetStatus(codes.Error, predErr.Error())
+		}
+		inferSpan.End()
+		if predErr != nil {
+			return nil, predErr
+		}
+
+		resp = PredictionResponse{
+			Prediction:   prediction,
+			Probability:  probability,
+			ModelName:    model.Name(),
+			ModelVersion: model.Version(),
+		}
+
+		payload, marshalErr := json.Marshal(resp)
+		if marshalErr == nil {
+			setCtx, setSpan := tracer.Start(ctx, "cache.set", commonAttrs)
+			s.redis.Set(setCtx, cacheKey, payload, 5*time.Minute)
+			setSpan.End()
+		}
+	}
+
+	resp.LatencyMs = float64(time.Since(start).Milliseconds())
+	resp.CacheHit = cacheHit
+
+	return &resp, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/galafis/realtime-ml-serving-api/server/batcher"
+	"github.com/galafis/realtime-ml-serving-api/server/registry"
+)
+
+// Author: Gabriel Demetrios Lafis
+
+func newTestService(t *testing.T) *PredictionService {
+	t.Helper()
+
+	dir := t.TempDir()
+	model := map[string]interface{}{
+		"name":    "iris_classifier",
+		"version": "1.0.0",
+		"weights": []float64{1, 1, 1},
+		"bias":    0,
+	}
+	data, err := json.Marshal(model)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "iris_classifier@1.0.0.json"), data, 0644))
+
+	reg := registry.New(dir)
+	_, err = reg.Load()
+	assert.NoError(t, err)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	return NewPredictionService(redisClient, reg)
+}
+
+func TestPredictionServicePredict(t *testing.T) {
+	svc := newTestService(t)
+
+	t.Run("Sum less than zero predicts class 0", func(t *testing.T) {
+		resp, err := svc.Predict(context.Background(), PredictionRequest{
+			ModelName: "iris_classifier",
+			Features:  []float64{-5.0, -5.0, -5.0},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, resp.Prediction)
+	})
+
+	t.Run("Sum greater than zero predicts class 1", func(t *testing.T) {
+		resp, err := svc.Predict(context.Background(), PredictionRequest{
+			ModelName: "iris_classifier",
+			Features:  []float64{1.0, 2.0, 3.0},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.Prediction)
+	})
+
+	t.Run("Feature count mismatch is a validation error", func(t *testing.T) {
+		_, err := svc.Predict(context.Background(), PredictionRequest{
+			ModelName: "iris_classifier",
+			Features:  []float64{1.0},
+		})
+
+		assert.Error(t, err)
+		var verr *ValidationError
+		assert.ErrorAs(t, err, &verr)
+	})
+
+	t.Run("Unknown model returns ErrModelNotFound", func(t *testing.T) {
+		_, err := svc.Predict(context.Background(), PredictionRequest{
+			ModelName: "does_not_exist",
+			Features:  []float64{1.0},
+		})
+
+		assert.ErrorIs(t, err, registry.ErrModelNotFound)
+	})
+
+	t.Run("Batcher closed by a reload in flight surfaces a clear error instead of hanging", func(t *testing.T) {
+		// Resolve and close the Batcher backing the loaded model directly,
+		// simulating the narrow window where Registry.Load closes it out
+		// from under a request that already resolved it. The registry
+		// itself hasn't reloaded, so Predict's retry re-resolves the same
+		// now-closed Batcher: this is the residual double-failure case
+		// documented in Predict, not the common single-reload case it
+		// recovers from, but it must still fail fast rather than hang.
+		_, modelBatcher, ok := svc.registry.Resolve("iris_classifier", "")
+		assert.True(t, ok)
+		modelBatcher.Close()
+
+		_, err := svc.Predict(context.Background(), PredictionRequest{
+			ModelName: "iris_classifier",
+			Features:  []float64{1.0, 2.0, 3.0},
+		})
+
+		assert.ErrorIs(t, err, batcher.ErrBatcherClosed)
+	})
+}
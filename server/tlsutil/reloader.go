@@ -0,0 +1,114 @@
+// Package tlsutil provides a reloadable TLS certificate for the HTTP
+// server, so operators can rotate certs without a restart.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"log"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// CertReloader watches a certificate/key pair on disk and atomically
+// swaps the *tls.Certificate served to new connections via
+// tls.Config.GetCertificate.
+type CertReloader struct {
+	certFile, keyFile string
+	cert              atomic.Pointer[tls.Certificate]
+}
+
+// NewCertReloader loads certFile/keyFile and starts watching for
+// changes to either.
+//
+// It watches the containing directories rather than the files
+// themselves: cert-manager, kubelet-projected Secrets, and mv-based
+// rollover scripts all rotate a cert by writing a new file and
+// renaming it over the old path, which unlinks the inode a file-level
+// watch is tracking (reported as Chmod then Remove, never Write or
+// Create) and leaves that watch permanently dead after the first
+// rotation. A directory watch's inode outlives its children being
+// replaced, so it keeps reporting renames/creates of certFile/keyFile
+// indefinitely.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := map[string]bool{filepath.Dir(certFile): true, filepath.Dir(keyFile): true}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+
+	go r.watch(watcher)
+
+	return r, nil
+}
+
+func (r *CertReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.cert.Store(&cert)
+	return nil
+}
+
+func (r *CertReloader) watch(watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+
+	certName := filepath.Base(r.certFile)
+	keyName := filepath.Base(r.keyFile)
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			// Kubernetes' Secret/ConfigMap volumes never touch tls.crt
+			// or tls.key directly after the initial mount: tls.crt is a
+			// symlink to "..data/tls.crt", and a rotation instead
+			// re-points the "..data" symlink atomically. Reload on
+			// that too, not just on the watched files' own basenames.
+			base := filepath.Base(event.Name)
+			if base != certName && base != keyName && base != "..data" {
+				continue
+			}
+			// Rename/Remove cover mv-based rotation (cert-manager,
+			// kubelet-projected Secrets): the new file lands under the
+			// same name via rename-over, which the directory watch
+			// reports as Create for the new inode and Rename/Remove for
+			// the old one. Reloading on every variant is harmless since
+			// reload() just re-reads both paths from disk.
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				log.Printf("tlsutil: failed to reload certificate: %v", err)
+			} else {
+				log.Println("tlsutil: certificate reloaded")
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("tlsutil: watcher error: %v", err)
+		}
+	}
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
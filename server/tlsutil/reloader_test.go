@@ -0,0 +1,151 @@
+package tlsutil
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Author: Gabriel Demetrios Lafis
+
+// writeSelfSignedCert (re)writes a self-signed cert/key pair at
+// dir/tls.{crt,key} with the given CommonName, so tests can exercise
+// CertReloader against real PEM-encoded material.
+func writeSelfSignedCert(t *testing.T, dir, commonName string) (certPath, keyPath string) {
+	t.Helper()
+	return writeSelfSignedCertNamed(t, filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), commonName)
+}
+
+// writeSelfSignedCertNamed is writeSelfSignedCert with caller-chosen
+// file names, so tests can stage a replacement under a temporary name
+// before renaming it over the watched path.
+func writeSelfSignedCertNamed(t *testing.T, certPath, keyPath, commonName string) (string, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	certOut, err := os.Create(certPath)
+	assert.NoError(t, err)
+	assert.NoError(t, pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	assert.NoError(t, certOut.Close())
+
+	keyOut, err := os.Create(keyPath)
+	assert.NoError(t, err)
+	assert.NoError(t, pem.Encode(keyOut, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	assert.NoError(t, keyOut.Close())
+
+	return certPath, keyPath
+}
+
+func leafCommonName(t *testing.T, reloader *CertReloader) string {
+	t.Helper()
+
+	cert, err := reloader.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, cert)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloaderLoadsInitialCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeSelfSignedCert(t, dir, "initial")
+
+	reloader, err := NewCertReloader(certPath, keyPath)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "initial", leafCommonName(t, reloader))
+}
+
+func TestCertReloaderReloadsOnWrite(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeSelfSignedCert(t, dir, "initial")
+
+	reloader, err := NewCertReloader(certPath, keyPath)
+	assert.NoError(t, err)
+
+	writeSelfSignedCert(t, dir, "rotated")
+
+	assert.Eventually(t, func() bool {
+		cert, err := reloader.GetCertificate(nil)
+		if err != nil || cert == nil {
+			return false
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		return err == nil && leaf.Subject.CommonName == "rotated"
+	}, time.Second, 10*time.Millisecond)
+}
+
+// writeSelfSignedCertViaRename writes a cert/key pair to temporary paths
+// and renames them over dir/tls.{crt,key}, the same write-then-rename
+// sequence cert-manager, kubelet-projected Secrets, and mv-based
+// rollover scripts use in production. Unlike writeSelfSignedCert, this
+// unlinks the original tls.crt/tls.key inodes instead of overwriting
+// their contents in place.
+func writeSelfSignedCertViaRename(t *testing.T, dir, commonName string) {
+	t.Helper()
+
+	// Writing under temporary names and renaming within dir (not
+	// across it) is what makes the kernel report Rename/Remove against
+	// the old tls.crt/tls.key and Create for the replacement, the same
+	// sequence a same-volume mv-based rollover produces. A cross-
+	// directory rename from t.TempDir() would instead surface as a
+	// plain Create on dir and wouldn't exercise the Rename/Remove
+	// handling this test is for.
+	tmpCert, tmpKey := writeSelfSignedCertNamed(t, filepath.Join(dir, commonName+".crt.tmp"), filepath.Join(dir, commonName+".key.tmp"), commonName)
+
+	assert.NoError(t, os.Rename(tmpCert, filepath.Join(dir, "tls.crt")))
+	assert.NoError(t, os.Rename(tmpKey, filepath.Join(dir, "tls.key")))
+}
+
+func TestCertReloaderReloadsOnRename(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeSelfSignedCert(t, dir, "initial")
+
+	reloader, err := NewCertReloader(certPath, keyPath)
+	assert.NoError(t, err)
+
+	writeSelfSignedCertViaRename(t, dir, "rotated-once")
+
+	assert.Eventually(t, func() bool {
+		return leafCommonName(t, reloader) == "rotated-once"
+	}, time.Second, 10*time.Millisecond)
+
+	// A rename unlinks the inode a file-level watch would have been
+	// tracking, which used to leave the watcher permanently dead after
+	// the first rotation. Confirm a second rename-based rotation still
+	// reloads.
+	writeSelfSignedCertViaRename(t, dir, "rotated-twice")
+
+	assert.Eventually(t, func() bool {
+		return leafCommonName(t, reloader) == "rotated-twice"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNewCertReloaderMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewCertReloader(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key"))
+	assert.Error(t, err)
+}
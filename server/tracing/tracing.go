@@ -0,0 +1,55 @@
+// Package tracing wires up OpenTelemetry for the service. Spans cross
+// the HTTP, cache, and model layers so a single trace shows where a
+// prediction request actually spent its time.
+package tracing
+
+import (
+	"context"
+	"log"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// ServiceName identifies this service in exported spans and is used as
+// the otelgin/tracer instrumentation name.
+const ServiceName = "realtime-ml-serving-api"
+
+// Init configures the global OpenTelemetry TracerProvider to export to
+// endpoint over OTLP/gRPC. With endpoint empty it leaves the default
+// no-op TracerProvider in place, so otel.Tracer(...).Start calls are
+// free and existing tests keep passing without a collector running.
+func Init(ctx context.Context, endpoint string) (shutdown func(context.Context) error, err error) {
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(semconv.ServiceName(ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	log.Printf("tracing: exporting spans to %s", endpoint)
+
+	return tp.Shutdown, nil
+}
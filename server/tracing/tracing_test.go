@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+// Author: Gabriel Demetrios Lafis
+
+func TestInitWithEmptyEndpointIsNoop(t *testing.T) {
+	shutdown, err := Init(context.Background(), "")
+	assert.NoError(t, err)
+	assert.NotNil(t, shutdown)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestInitWithEmptyEndpointLeavesTracerUsable(t *testing.T) {
+	_, err := Init(context.Background(), "")
+	assert.NoError(t, err)
+
+	tracer := otel.Tracer(ServiceName)
+	_, span := tracer.Start(context.Background(), "test-span")
+	defer span.End()
+
+	assert.NotNil(t, span)
+}